@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/NebulousLabs/Sia/modules/renter"
+)
+
+// API implements and serves the renter's debug/diagnostics HTTP API. The
+// rest of the daemon's routes (consensus, wallet, host, ...) are registered
+// elsewhere; this file only wires up the renter endpoints this package
+// adds.
+type API struct {
+	renter *renter.Renter
+	router *httprouter.Router
+}
+
+// New returns an API with all of its routes registered against the given
+// renter.
+func New(r *renter.Renter) *API {
+	api := &API{renter: r}
+	api.router = httprouter.New()
+	api.buildHTTPRoutes()
+	return api
+}
+
+// ServeHTTP implements http.Handler by delegating to the underlying router.
+func (api *API) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	api.router.ServeHTTP(w, req)
+}
+
+// buildHTTPRoutes registers every renter debug/diagnostics route this
+// package serves.
+func (api *API) buildHTTPRoutes() {
+	api.router.GET("/renter/progress", api.renterProgressHandlerGET)
+	api.router.GET("/renter/hostactivity", api.renterHostActivityHandlerGET)
+	api.router.GET("/renter/hostactivitytotals", api.renterHostActivityTotalsHandlerGET)
+	api.router.GET("/renter/repairpath/:siapath", api.renterRepairPathHandlerGET)
+	api.router.POST("/renter/repairstrategy", api.renterRepairStrategyHandlerPOST)
+	api.router.POST("/renter/scanandrepair", api.renterScanAndRepairHandlerPOST)
+	api.router.POST("/renter/testhostfetcher", api.renterTestHostFetcherHandlerPOST)
+	api.router.POST("/renter/testpiecehosts", api.renterTestPieceHostsHandlerPOST)
+}
+
+// Error is the JSON representation of an API error.
+type Error struct {
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (err Error) Error() string {
+	return err.Message
+}
+
+// WriteJSON writes the JSON encoding of obj to w.
+func WriteJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+// WriteError writes err to w as JSON with the given HTTP status code.
+func WriteError(w http.ResponseWriter, err Error, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(err)
+}
+
+// WriteSuccess writes a 204 No Content response, the convention this API
+// uses for calls that succeed without returning a body.
+func WriteSuccess(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
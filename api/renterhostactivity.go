@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// renterHostActivityHandlerGET handles the API call to
+// /renter/hostactivity, returning the number of in-flight requests per
+// host the renter is currently tracking.
+func (api *API) renterHostActivityHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, api.renter.HostActivityCounts())
+}
+
+// renterHostActivityTotalsHandlerGET handles the API call to
+// /renter/hostactivitytotals, returning the cumulative number of requests
+// ever issued per host.
+func (api *API) renterHostActivityTotalsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, api.renter.HostActivityTotals())
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// renterProgressHandlerGET handles the API call to /renter/progress,
+// returning the origin/current/highest byte counters of the most recent
+// repair run of every file the renter has repaired. Uploads and downloads
+// are not reported.
+func (api *API) renterProgressHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, api.renter.Progress())
+}
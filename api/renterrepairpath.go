@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// renterRepairPathHandlerGET handles the API call to
+// /renter/repairpath/:siapath, returning which pool ("copier", "puller", or
+// "copier+puller") most recently handled the file's repair.
+func (api *API) renterRepairPathHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	siaPath := ps.ByName("siapath")
+	path, ok := api.renter.RepairPath(siaPath)
+	if !ok {
+		WriteError(w, Error{"no repair recorded for that file"}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, struct {
+		Path string `json:"path"`
+	}{path})
+}
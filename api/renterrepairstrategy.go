@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// renterRepairStrategyHandlerPOST handles the API call to
+// /renter/repairstrategy, letting the active RepairStrategy be switched at
+// runtime without restarting the renter.
+//
+// strategy=eager switches to eager repair (repair as soon as redundancy
+// drops). strategy=threshold switches to threshold repair, deferring until
+// redundancy falls below the floor given in the floor query parameter.
+func (api *API) renterRepairStrategyHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var floor float64
+	if req.FormValue("strategy") == "threshold" {
+		var err error
+		floor, err = strconv.ParseFloat(req.FormValue("floor"), 64)
+		if err != nil {
+			WriteError(w, Error{"invalid floor: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if err := api.renter.SetRepairStrategy(req.FormValue("strategy"), floor); err != nil {
+		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
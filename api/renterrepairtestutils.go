@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/NebulousLabs/Sia/modules/renter"
+)
+
+// renterTestHostFetcherHandlerPOST handles the API call to
+// /renter/testhostfetcher, wiring the renter's HostPieceFetcher to a stub
+// that always succeeds. It exists so integration tests can exercise the
+// puller pool end to end without a live renter-host protocol
+// implementation; production renters wire a real HostPieceFetcher at
+// startup instead and never call this route.
+func (api *API) renterTestHostFetcherHandlerPOST(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	api.renter.SetHostPieceFetcher(renter.NewAlwaysSucceedsHostPieceFetcher())
+	WriteSuccess(w)
+}
+
+// renterTestPieceHostsHandlerPOST handles the API call to
+// /renter/testpiecehosts, recording which hosts are known to hold a given
+// piece of a file. In a full renter this bookkeeping comes from the
+// contractor/host database as contracts are formed; this route lets
+// integration tests populate it directly since that machinery isn't part
+// of this package.
+func (api *API) renterTestPieceHostsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	piece, err := strconv.ParseUint(req.FormValue("piece"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"invalid piece: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var hosts []renter.HostKey
+	if raw := req.FormValue("hosts"); raw != "" {
+		for _, h := range strings.Split(raw, ",") {
+			hosts = append(hosts, renter.HostKey(h))
+		}
+	}
+	api.renter.RegisterPieceHosts(req.FormValue("siapath"), piece, hosts)
+	WriteSuccess(w)
+}
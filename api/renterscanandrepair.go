@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// renterScanAndRepairHandlerPOST handles the API call to
+// /renter/scanandrepair, triggering an immediate, synchronous repair scan of
+// one file instead of waiting for the renter's background repair scan loop
+// to pick it up on its own schedule. degradedpieces is a comma-separated
+// list of the piece indices that are currently known to need repair; the
+// caller (normally the renter's own contractor/host database, here the
+// caller driving this API directly) is responsible for knowing which
+// indices those are, since the renter's repair strategies no longer guess
+// at them.
+func (api *API) renterScanAndRepairHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	siaPath := req.FormValue("siapath")
+	redundancy, err := strconv.ParseFloat(req.FormValue("redundancy"), 64)
+	if err != nil {
+		WriteError(w, Error{"invalid redundancy: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	var degraded []uint64
+	if raw := req.FormValue("degradedpieces"); raw != "" {
+		for _, s := range strings.Split(raw, ",") {
+			piece, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				WriteError(w, Error{"invalid degradedpieces: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			degraded = append(degraded, piece)
+		}
+	}
+
+	fileInfo := modules.FileInfo{
+		SiaPath:    siaPath,
+		Redundancy: redundancy,
+		LocalPath:  req.FormValue("localpath"),
+	}
+	if len(degraded) > 0 {
+		api.renter.MarkPiecesDegraded(siaPath, degraded)
+	}
+	if err := api.renter.ScanAndRepair(siaPath, fileInfo); err != nil {
+		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
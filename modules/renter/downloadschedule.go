@@ -0,0 +1,130 @@
+package renter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// errHostFetcherNotConfigured is returned by the default HostPieceFetcher
+// when no real implementation has been wired in via SetHostPieceFetcher.
+// downloadPieceFromHosts still does everything it owns (host selection,
+// hostActivity accounting) for real; only the actual host-protocol round
+// trip is left to whatever layer manages contracts and connections.
+var errHostFetcherNotConfigured = errors.New("no host piece fetcher configured")
+
+// HostPieceFetcher fetches a single erasure-coded piece from a host over
+// the renter-host protocol. It is injected via SetHostPieceFetcher so that
+// downloadPieceFromHosts can be exercised without a live host connection.
+type HostPieceFetcher interface {
+	FetchPiece(hostKey HostKey, fileInfo modules.FileInfo, piece uint64) error
+}
+
+// hostPieceFetcherFunc lets a plain function satisfy HostPieceFetcher.
+type hostPieceFetcherFunc func(hostKey HostKey, fileInfo modules.FileInfo, piece uint64) error
+
+// FetchPiece implements HostPieceFetcher.
+func (f hostPieceFetcherFunc) FetchPiece(hostKey HostKey, fileInfo modules.FileInfo, piece uint64) error {
+	return f(hostKey, fileInfo, piece)
+}
+
+// NewAlwaysSucceedsHostPieceFetcher returns a HostPieceFetcher that
+// immediately succeeds for any host and piece, without performing a real
+// renter-host round trip. It exists so integration tests can exercise the
+// puller pool end to end without a live renter-host protocol implementation
+// in this package; production renters must call SetHostPieceFetcher with a
+// real one instead.
+func NewAlwaysSucceedsHostPieceFetcher() HostPieceFetcher {
+	return hostPieceFetcherFunc(func(HostKey, modules.FileInfo, uint64) error {
+		return nil
+	})
+}
+
+// SetHostPieceFetcher replaces the renter's HostPieceFetcher. Tests use this
+// to simulate hosts serving (or failing to serve) a piece without a live
+// renter-host connection.
+func (r *Renter) SetHostPieceFetcher(f HostPieceFetcher) {
+	r.hostFetcherMu.Lock()
+	r.hostFetcher = f
+	r.hostFetcherMu.Unlock()
+}
+
+// RegisterPieceHosts records which hosts currently hold piece of the file at
+// siaPath, as learned by the renter's contract/host-database bookkeeping
+// elsewhere in the package. downloadPieceFromHosts consults this to know
+// which hosts are even candidates for a given piece.
+func (r *Renter) RegisterPieceHosts(siaPath string, piece uint64, hosts []HostKey) {
+	r.pieceHostsMu.Lock()
+	defer r.pieceHostsMu.Unlock()
+	if r.pieceHosts[siaPath] == nil {
+		r.pieceHosts[siaPath] = make(map[uint64][]HostKey)
+	}
+	stored := make([]HostKey, len(hosts))
+	copy(stored, hosts)
+	r.pieceHosts[siaPath][piece] = stored
+}
+
+// hostsForPiece returns the hosts known to hold piece for fileInfo, as
+// previously recorded via RegisterPieceHosts.
+func (r *Renter) hostsForPiece(fileInfo modules.FileInfo, piece uint64) []HostKey {
+	r.pieceHostsMu.Lock()
+	defer r.pieceHostsMu.Unlock()
+	hosts := r.pieceHosts[fileInfo.SiaPath][piece]
+	candidates := make([]HostKey, len(hosts))
+	copy(candidates, hosts)
+	return candidates
+}
+
+// fetchPieceFromHost fetches piece from hostKey via the renter's
+// HostPieceFetcher.
+func (r *Renter) fetchPieceFromHost(hostKey HostKey, fileInfo modules.FileInfo, piece uint64) error {
+	r.hostFetcherMu.Lock()
+	fetcher := r.hostFetcher
+	r.hostFetcherMu.Unlock()
+	if fetcher == nil {
+		return errHostFetcherNotConfigured
+	}
+	return fetcher.FetchPiece(hostKey, fileInfo, piece)
+}
+
+// downloadPieceFromHosts reconstructs piece of fileInfo by fetching it from
+// whichever of the piece's surviving hosts is currently least busy,
+// skipping any host the repair has already tried and failed against. It
+// reports the host it used (or attempted) so callers can record a failure
+// against the right host.
+func (r *Renter) downloadPieceFromHosts(state *sharedRepairState, fileInfo modules.FileInfo, piece uint64) (HostKey, error) {
+	candidates := r.hostsForPiece(fileInfo, piece)
+
+	state.mu.Lock()
+	var available []HostKey
+	for _, host := range candidates {
+		if !state.failedHosts[host] {
+			available = append(available, host)
+		}
+	}
+	state.mu.Unlock()
+
+	hostKey, ok := r.pickHostForPiece(available)
+	if !ok {
+		return "", fmt.Errorf("no surviving host available for piece %v", piece)
+	}
+
+	r.hostActivity.UsingHost(hostKey)
+	defer r.hostActivity.done(hostKey)
+
+	if err := r.fetchPieceFromHost(hostKey, fileInfo, piece); err != nil {
+		return hostKey, err
+	}
+	return hostKey, nil
+}
+
+// pickHostForPiece selects which host a download or repair worker should
+// fetch a piece from, given the set of hosts known to hold it. It defers to
+// r.hostActivity so that requests are spread across hosts instead of always
+// landing on whichever candidate happens to be first in the list. The
+// second return value is false if no candidate is available to serve the
+// piece right now.
+func (r *Renter) pickHostForPiece(candidates []HostKey) (HostKey, bool) {
+	return r.hostActivity.leastBusy(candidates)
+}
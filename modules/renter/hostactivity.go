@@ -0,0 +1,102 @@
+package renter
+
+import (
+	"sync"
+)
+
+// HostKey identifies a host by its public key string, as used throughout
+// the renter's download and repair scheduling code.
+type HostKey string
+
+// hostActivity tracks the number of in-flight requests per host so the
+// download and repair schedulers can favor whichever host among a set of
+// candidates currently has the least work outstanding, instead of picking
+// one in arbitrary order. It also keeps a cumulative per-host request
+// count, since the in-flight count alone can't answer "how evenly was load
+// spread over the life of an operation" once everything has finished.
+type hostActivity struct {
+	mu     sync.Mutex
+	active map[HostKey]int
+	total  map[HostKey]int
+}
+
+// newHostActivity returns an empty activity tracker.
+func newHostActivity() *hostActivity {
+	return &hostActivity{
+		active: make(map[HostKey]int),
+		total:  make(map[HostKey]int),
+	}
+}
+
+// UsingHost records that a new request against hostKey has started.
+func (ha *hostActivity) UsingHost(hostKey HostKey) {
+	ha.mu.Lock()
+	ha.active[hostKey]++
+	ha.total[hostKey]++
+	ha.mu.Unlock()
+}
+
+// done records that a request against hostKey has finished. It is the
+// caller's responsibility to pair every UsingHost call with a done call,
+// typically via defer.
+func (ha *hostActivity) done(hostKey HostKey) {
+	ha.mu.Lock()
+	defer ha.mu.Unlock()
+	ha.active[hostKey]--
+	if ha.active[hostKey] <= 0 {
+		delete(ha.active, hostKey)
+	}
+}
+
+// leastBusy returns whichever of candidates currently has the fewest
+// in-flight requests. Ties are broken in favor of the earliest candidate in
+// the slice so the result is deterministic. The second return value is
+// false if candidates is empty, which is an ordinary runtime condition
+// (host churn, eviction, a temporary disconnect) rather than a caller bug,
+// so callers must check it instead of getting a panic.
+func (ha *hostActivity) leastBusy(candidates []HostKey) (HostKey, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	ha.mu.Lock()
+	defer ha.mu.Unlock()
+
+	best := candidates[0]
+	bestCount := ha.active[best]
+	for _, candidate := range candidates[1:] {
+		if count := ha.active[candidate]; count < bestCount {
+			best = candidate
+			bestCount = count
+		}
+	}
+	return best, true
+}
+
+// HostActivityCounts returns a snapshot of the number of in-flight requests
+// per host.
+func (r *Renter) HostActivityCounts() map[HostKey]int {
+	r.hostActivity.mu.Lock()
+	defer r.hostActivity.mu.Unlock()
+
+	counts := make(map[HostKey]int, len(r.hostActivity.active))
+	for hostKey, count := range r.hostActivity.active {
+		counts[hostKey] = count
+	}
+	return counts
+}
+
+// HostActivityTotals returns a snapshot of the cumulative number of
+// requests ever issued per host, primarily for diagnostics and tests
+// asserting that load was spread evenly across hosts over the life of an
+// operation, after any in-flight requests have already completed.
+func (r *Renter) HostActivityTotals() map[HostKey]int {
+	r.hostActivity.mu.Lock()
+	defer r.hostActivity.mu.Unlock()
+
+	totals := make(map[HostKey]int, len(r.hostActivity.total))
+	for hostKey, count := range r.hostActivity.total {
+		totals[hostKey] = count
+	}
+	return totals
+}
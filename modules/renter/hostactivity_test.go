@@ -0,0 +1,46 @@
+package renter
+
+import (
+	"testing"
+)
+
+// TestHostActivityLeastBusy verifies that leastBusy always returns the
+// candidate with the fewest in-flight requests, and that UsingHost/done
+// correctly move a host's count up and down.
+func TestHostActivityLeastBusy(t *testing.T) {
+	ha := newHostActivity()
+	hosts := []HostKey{"host1", "host2", "host3"}
+
+	// With no activity recorded, the first candidate should win the tie.
+	if got, ok := ha.leastBusy(hosts); !ok || got != hosts[0] {
+		t.Fatalf("expected %v to be least busy, got %v (ok=%v)", hosts[0], got, ok)
+	}
+
+	// Load up host1 and host2, leaving host3 the least busy.
+	ha.UsingHost(hosts[0])
+	ha.UsingHost(hosts[0])
+	ha.UsingHost(hosts[1])
+	if got, ok := ha.leastBusy(hosts); !ok || got != hosts[2] {
+		t.Fatalf("expected %v to be least busy, got %v (ok=%v)", hosts[2], got, ok)
+	}
+
+	// Finishing host1's requests should make it the least busy again.
+	ha.done(hosts[0])
+	ha.done(hosts[0])
+	if got, ok := ha.leastBusy(hosts); !ok || got != hosts[0] {
+		t.Fatalf("expected %v to be least busy, got %v (ok=%v)", hosts[0], got, ok)
+	}
+
+	// done should never leave a negative count lingering in the map.
+	ha.done(hosts[0])
+	ha.mu.Lock()
+	if count, ok := ha.active[hosts[0]]; ok {
+		t.Fatalf("expected host1 to be cleared from the map, got count %v", count)
+	}
+	ha.mu.Unlock()
+
+	// An empty candidate list has no least-busy host to return.
+	if _, ok := ha.leastBusy(nil); ok {
+		t.Fatal("expected leastBusy to report false for an empty candidate list")
+	}
+}
@@ -0,0 +1,117 @@
+package renter
+
+import (
+	"sync"
+)
+
+// ProgressInfo is a snapshot of the starting, current, and highest-known
+// position of a single upload, download, or repair operation, all measured
+// in bytes. It lets callers report "x of y" progress without having to
+// infer it from redundancy or other indirect signals.
+type ProgressInfo struct {
+	Origin  uint64 `json:"origin"`
+	Current uint64 `json:"current"`
+	Highest uint64 `json:"highest"`
+}
+
+// syncInitHook, if non-nil, is called with the origin and target byte
+// counts the instant a new sync/repair run begins for a tracked operation.
+// It is overwritten by tests that need to assert on the exact values a
+// repair starts with, without racing the repair loop to observe them.
+var syncInitHook = func(origin, target uint64) {}
+
+// progressTracker records the origin/current/highest counters for a single
+// active file operation. All fields are guarded by mu so the tracker can be
+// read from the API goroutine while the repair/upload/download loop that
+// owns it keeps advancing.
+type progressTracker struct {
+	mu      sync.RWMutex
+	origin  uint64
+	current uint64
+	highest uint64
+}
+
+// newProgressTracker returns an empty tracker. Callers must call reset
+// before the counters are meaningful.
+func newProgressTracker() *progressTracker {
+	return &progressTracker{}
+}
+
+// reset reinitializes the counters for a new sync/repair run starting at
+// origin and targeting target, and fires syncInitHook with those values.
+func (pt *progressTracker) reset(origin, target uint64) {
+	pt.mu.Lock()
+	pt.origin = origin
+	pt.current = origin
+	pt.highest = origin
+	pt.mu.Unlock()
+	if syncInitHook != nil {
+		syncInitHook(origin, target)
+	}
+}
+
+// advance moves current forward to pos, bumping highest if pos surpasses
+// it. It is a no-op if pos is behind the current position.
+func (pt *progressTracker) advance(pos uint64) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if pos <= pt.current {
+		return
+	}
+	pt.current = pos
+	if pos > pt.highest {
+		pt.highest = pos
+	}
+}
+
+// snapshot returns the tracker's current origin/current/highest counters.
+func (pt *progressTracker) snapshot() ProgressInfo {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+	return ProgressInfo{
+		Origin:  pt.origin,
+		Current: pt.current,
+		Highest: pt.highest,
+	}
+}
+
+// Progress returns a snapshot of the origin/current/highest byte counters
+// for every file the renter has run a repair on, keyed by the SiaPath of
+// that file. Only repairs are tracked; uploads and downloads get no entry.
+// A file's entry stays after its repair finishes and holds the counters of
+// its most recent repair run.
+//
+// Progress is safe to call concurrently with the renter's upload, download,
+// and repair loops; it takes r.progressMu for reading only.
+func (r *Renter) Progress() map[string]ProgressInfo {
+	r.progressMu.RLock()
+	defer r.progressMu.RUnlock()
+
+	snapshot := make(map[string]ProgressInfo, len(r.progress))
+	for siaPath, tracker := range r.progress {
+		snapshot[siaPath] = tracker.snapshot()
+	}
+	return snapshot
+}
+
+// trackProgress returns the progressTracker for siaPath, creating one if
+// this is the first operation tracked for that file.
+func (r *Renter) trackProgress(siaPath string) *progressTracker {
+	r.progressMu.Lock()
+	defer r.progressMu.Unlock()
+
+	pt, ok := r.progress[siaPath]
+	if !ok {
+		pt = newProgressTracker()
+		r.progress[siaPath] = pt
+	}
+	return pt
+}
+
+// clearProgress drops the tracker for siaPath once the operation it was
+// following has finished.
+func (r *Renter) clearProgress(siaPath string) {
+	r.progressMu.Lock()
+	delete(r.progress, siaPath)
+	r.progressMu.Unlock()
+}
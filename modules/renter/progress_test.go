@@ -0,0 +1,56 @@
+package renter
+
+import (
+	"os"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestSyncInitHookFiresWithRealValues verifies that a real repair run fires
+// syncInitHook with the origin/target byte counts it actually starts with,
+// rather than syncInitHook only ever existing as an override point nothing
+// calls.
+func TestSyncInitHookFiresWithRealValues(t *testing.T) {
+	localCopy, err := os.CreateTemp("", "progress-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(localCopy.Name())
+	const fileSize = 4096
+	if _, err := localCopy.Write(make([]byte, fileSize)); err != nil {
+		t.Fatal(err)
+	}
+	localCopy.Close()
+
+	oldHook := syncInitHook
+	defer func() { syncInitHook = oldHook }()
+
+	var gotOrigin, gotTarget uint64
+	called := false
+	syncInitHook = func(origin, target uint64) {
+		called = true
+		gotOrigin, gotTarget = origin, target
+	}
+
+	r := New()
+	defer r.Close()
+
+	siaPath := "synchooktest"
+	fileInfo := modules.FileInfo{
+		SiaPath:   siaPath,
+		LocalPath: localCopy.Name(),
+		Filesize:  fileSize,
+	}
+	r.MarkPiecesDegraded(siaPath, []uint64{0})
+	if err := r.ScanAndRepair(siaPath, fileInfo); err != nil {
+		t.Fatalf("ScanAndRepair failed: %v", err)
+	}
+
+	if !called {
+		t.Fatal("expected syncInitHook to be called by a real repair run")
+	}
+	if gotOrigin != 0 || gotTarget != fileSize {
+		t.Fatalf("syncInitHook got origin=%v target=%v, want origin=0 target=%v", gotOrigin, gotTarget, fileSize)
+	}
+}
@@ -0,0 +1,82 @@
+package renter
+
+import (
+	"sync"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// Renter is the renter's top-level state. This file declares the subset of
+// fields that the progress-tracking, host-activity, and repair-state
+// subsystems in this package need; the renter's contractor, host database,
+// and on-disk persistence live in the rest of the package.
+type Renter struct {
+	mu sync.RWMutex
+
+	settings modules.RenterSettings
+
+	// progress backs Progress(); see progress.go.
+	progress   map[string]*progressTracker
+	progressMu sync.RWMutex
+
+	// hostActivity backs pickHostForPiece/HostActivityCounts; see
+	// hostactivity.go.
+	hostActivity *hostActivity
+
+	// repairPath backs RepairPath(); see repairpools.go.
+	repairPath   map[string]string
+	repairPathMu sync.RWMutex
+
+	// pieceHealth records, per file, which piece indices are currently
+	// known to be degraded; see repairstrategy.go.
+	pieceHealth   map[string]map[uint64]bool
+	pieceHealthMu sync.Mutex
+
+	// pieceHosts records which hosts are known to hold each piece of a
+	// file, backing hostsForPiece; see downloadschedule.go.
+	pieceHosts   map[string]map[uint64][]HostKey
+	pieceHostsMu sync.Mutex
+
+	// hostFetcher performs the actual renter-host protocol round trip for
+	// fetchPieceFromHost; see downloadschedule.go.
+	hostFetcher   HostPieceFetcher
+	hostFetcherMu sync.Mutex
+
+	// contractSet records, per file, the hosts whose pieces were most
+	// recently committed by the finisher; see repairpools.go.
+	contractSet   map[string][]HostKey
+	contractSetMu sync.Mutex
+
+	// trackedFiles holds the latest known modules.FileInfo snapshot for
+	// each file under background repair tracking, fed by RegisterFile and
+	// consumed by threadedRepairScan; see repairloop.go.
+	trackedFiles   map[string]modules.FileInfo
+	trackedFilesMu sync.Mutex
+
+	// closed stops threadedRepairScan when the renter shuts down.
+	closed chan struct{}
+}
+
+// New returns a Renter with all of its tracking subsystems initialized and
+// ready to use. It starts the background repair scan loop; callers should
+// call Close when finished with the Renter to stop it.
+func New() *Renter {
+	r := &Renter{
+		progress:         make(map[string]*progressTracker),
+		hostActivity:     newHostActivity(),
+		repairPath:       make(map[string]string),
+		pieceHealth:      make(map[string]map[uint64]bool),
+		pieceHosts:       make(map[string]map[uint64][]HostKey),
+		contractSet:      make(map[string][]HostKey),
+		trackedFiles:     make(map[string]modules.FileInfo),
+		closed:           make(chan struct{}),
+	}
+	go r.threadedRepairScan()
+	return r
+}
+
+// Close stops the renter's background repair scan loop. Safe to call once.
+func (r *Renter) Close() error {
+	close(r.closed)
+	return nil
+}
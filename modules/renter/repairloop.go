@@ -0,0 +1,79 @@
+package renter
+
+import (
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// repairScanInterval is how often threadedRepairScan re-evaluates every
+// tracked file against the renter's active RepairStrategy.
+const repairScanInterval = 500 * time.Millisecond
+
+// RegisterFile tells the renter to start (or update) background repair
+// tracking for siaPath, using fileInfo as its latest known state. Whatever
+// part of the renter learns a file's current redundancy and local path
+// (the upload/download manager, in the full renter) calls this whenever
+// that state changes, so threadedRepairScan always scans against a fresh
+// snapshot rather than one that's gone stale.
+func (r *Renter) RegisterFile(siaPath string, fileInfo modules.FileInfo) {
+	r.trackedFilesMu.Lock()
+	r.trackedFiles[siaPath] = fileInfo
+	r.trackedFilesMu.Unlock()
+}
+
+// UnregisterFile stops background repair tracking for siaPath, e.g. once
+// the file has been deleted.
+func (r *Renter) UnregisterFile(siaPath string) {
+	r.trackedFilesMu.Lock()
+	delete(r.trackedFiles, siaPath)
+	r.trackedFilesMu.Unlock()
+}
+
+// threadedRepairScan is the renter's repair scheduler: on every tick, it
+// runs scanAndRepair against the latest registered snapshot of every
+// tracked file, driving the copier/puller/finisher pools whenever the
+// active RepairStrategy decides a file needs it. It replaces the ad-hoc,
+// inline repair loop this package used to have, and runs until Close is
+// called.
+func (r *Renter) threadedRepairScan() {
+	ticker := time.NewTicker(repairScanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.closed:
+			return
+		case <-ticker.C:
+			r.scanTrackedFiles()
+		}
+	}
+}
+
+// scanTrackedFiles runs one scanAndRepair pass over every file currently
+// registered via RegisterFile.
+func (r *Renter) scanTrackedFiles() {
+	r.trackedFilesMu.Lock()
+	files := make(map[string]modules.FileInfo, len(r.trackedFiles))
+	for siaPath, fileInfo := range r.trackedFiles {
+		files[siaPath] = fileInfo
+	}
+	r.trackedFilesMu.Unlock()
+
+	for siaPath, fileInfo := range files {
+		// Repairs in this package don't yet have anything in flight for
+		// scanAndRepair itself to cancel; repairFile's cancel only tears
+		// down uploads started by a prior, still-running repair attempt
+		// for the same file, which can't happen here since scans run
+		// sequentially.
+		_ = r.scanAndRepair(siaPath, fileInfo, func() {})
+	}
+}
+
+// ScanAndRepair runs a single, synchronous repair pass for fileInfo right
+// now, without waiting for the next background scan tick. The API's
+// /renter/scanandrepair call uses this so tests can drive a deterministic
+// repair instead of racing threadedRepairScan's timer.
+func (r *Renter) ScanAndRepair(siaPath string, fileInfo modules.FileInfo) error {
+	r.RegisterFile(siaPath, fileInfo)
+	return r.scanAndRepair(siaPath, fileInfo, func() {})
+}
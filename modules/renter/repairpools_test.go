@@ -0,0 +1,86 @@
+package renter
+
+import (
+	"os"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestScanAndRepairCopierPath verifies that ScanAndRepair, the entry point
+// the background repair scan loop and the /renter/scanandrepair API call
+// both use, actually drives a file through the copier pool, records the
+// repair path, advances real progress, and heals the piece's degraded
+// marker, rather than any of that being unreachable.
+func TestScanAndRepairCopierPath(t *testing.T) {
+	r := New()
+	defer r.Close()
+
+	localCopy, err := os.CreateTemp("", "repairpools-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(localCopy.Name())
+	const fileSize = 4096
+	if _, err := localCopy.Write(make([]byte, fileSize)); err != nil {
+		t.Fatal(err)
+	}
+	localCopy.Close()
+
+	siaPath := "copiertest"
+	fileInfo := modules.FileInfo{
+		SiaPath:   siaPath,
+		LocalPath: localCopy.Name(),
+		Filesize:  fileSize,
+	}
+	r.MarkPiecesDegraded(siaPath, []uint64{0})
+
+	if err := r.ScanAndRepair(siaPath, fileInfo); err != nil {
+		t.Fatalf("ScanAndRepair failed: %v", err)
+	}
+
+	path, ok := r.RepairPath(siaPath)
+	if !ok || path != "copier" {
+		t.Fatalf("expected repair path %q, got %q (ok=%v)", "copier", path, ok)
+	}
+
+	progress, ok := r.Progress()[siaPath]
+	if !ok {
+		t.Fatal("expected Progress() to report an entry for the repaired file")
+	}
+	if progress.Current != fileSize || progress.Highest != fileSize {
+		t.Fatalf("expected progress to reach %v, got %+v", fileSize, progress)
+	}
+
+	if degraded := r.degradedPieces(fileInfo); len(degraded) != 0 {
+		t.Fatalf("expected no pieces to remain degraded after repair, got %v", degraded)
+	}
+}
+
+// TestScanAndRepairPullerPath verifies the puller/finisher path: with no
+// local copy available, ScanAndRepair must reconstruct the piece from a
+// host and commit it to the file's contract set.
+func TestScanAndRepairPullerPath(t *testing.T) {
+	r := New()
+	defer r.Close()
+	r.SetHostPieceFetcher(NewAlwaysSucceedsHostPieceFetcher())
+
+	siaPath := "pullertest"
+	fileInfo := modules.FileInfo{SiaPath: siaPath, Filesize: 4096}
+	r.MarkPiecesDegraded(siaPath, []uint64{0})
+	r.RegisterPieceHosts(siaPath, 0, []HostKey{"host1"})
+
+	if err := r.ScanAndRepair(siaPath, fileInfo); err != nil {
+		t.Fatalf("ScanAndRepair failed: %v", err)
+	}
+
+	path, ok := r.RepairPath(siaPath)
+	if !ok || path != "puller" {
+		t.Fatalf("expected repair path %q, got %q (ok=%v)", "puller", path, ok)
+	}
+
+	hosts := r.ContractSet(siaPath)
+	if len(hosts) != 1 || hosts[0] != "host1" {
+		t.Fatalf("expected contract set [host1], got %v", hosts)
+	}
+}
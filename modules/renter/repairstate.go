@@ -0,0 +1,198 @@
+package renter
+
+import (
+	"sync"
+)
+
+// repairPoolSize bounds how many goroutines each of the copier and puller
+// pools may run concurrently on behalf of a single file. The finisher runs
+// once per file and does not use it.
+const repairPoolSize = 10
+
+// sharedRepairState is the single point of coordination for repairing one
+// file. One instance exists per file currently under repair, and it is
+// shared by that file's copier, puller, and finisher goroutines, so all of
+// its methods must be safe for concurrent use.
+type sharedRepairState struct {
+	mu sync.Mutex
+
+	// healthy are piece indices that already meet redundancy and need no
+	// further work.
+	healthy map[uint64]bool
+	// copying are piece indices currently being read back from the local
+	// copy of the file, if one is available on disk.
+	copying map[uint64]bool
+	// pulling are piece indices currently being reconstructed by
+	// downloading surviving shards from remote hosts.
+	pulling map[uint64]bool
+	// failedHosts records hosts that have already been tried and failed
+	// while repairing this file, so the puller doesn't retry them.
+	failedHosts map[HostKey]bool
+
+	// pieceHosts records which host served each piece the puller
+	// recovered, so the finisher knows the new contract set to commit.
+	// Pieces the copier recovered locally don't get an entry here, since
+	// recovering them didn't change which host holds them.
+	pieceHosts map[uint64]HostKey
+
+	pending int // outstanding copier+puller work items
+	err     error
+	done    bool
+
+	// copiedAny/pulledAny record whether the copier or puller pool ever
+	// handled a piece for this file, so tests can assert which path a
+	// repair took.
+	copiedAny bool
+	pulledAny bool
+
+	// cancel tears down any in-flight uploads for this file. It is called
+	// exactly once, the first time the state is marked failed, so that
+	// failed repairs don't leak uploads.
+	cancel func()
+}
+
+// newSharedRepairState returns a state for a repair that works on numPieces
+// pieces of a file. cancel is invoked if the repair fails, to stop any uploads that
+// are already in flight for this file.
+func newSharedRepairState(numPieces int, cancel func()) *sharedRepairState {
+	return &sharedRepairState{
+		healthy:     make(map[uint64]bool, numPieces),
+		copying:     make(map[uint64]bool),
+		pulling:     make(map[uint64]bool),
+		failedHosts: make(map[HostKey]bool),
+		pieceHosts:  make(map[uint64]HostKey),
+		cancel:      cancel,
+	}
+}
+
+// startCopy records that piece is being read back from the local copy of
+// the file.
+func (s *sharedRepairState) startCopy(piece uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.copying[piece] = true
+	s.pending++
+}
+
+// startPull records that piece is being reconstructed by downloading
+// surviving shards from remote hosts.
+func (s *sharedRepairState) startPull(piece uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pulling[piece] = true
+	s.pending++
+}
+
+// finishCopy marks piece as healthy and clears it from the copier's
+// in-progress set.
+func (s *sharedRepairState) finishCopy(piece uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.copying, piece)
+	s.healthy[piece] = true
+	s.copiedAny = true
+	s.pending--
+}
+
+// abandonCopy gives up on reading piece from the local copy of the file
+// (because it wasn't there, not because of a genuine error) without
+// marking the piece healthy or the whole repair failed, so the puller can
+// still pick it up.
+func (s *sharedRepairState) abandonCopy(piece uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.copying, piece)
+	s.pending--
+}
+
+// finishPull marks piece as healthy, clears it from the puller's
+// in-progress set, and records hostKey as the host that served it, so the
+// finisher can commit it into the file's new contract set.
+func (s *sharedRepairState) finishPull(piece uint64, hostKey HostKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pulling, piece)
+	s.healthy[piece] = true
+	s.pulledAny = true
+	s.pieceHosts[piece] = hostKey
+	s.pending--
+}
+
+// usedHosts returns the distinct hosts the puller used to recover pieces
+// for this file, for the finisher to commit as (part of) the file's new
+// contract set.
+func (s *sharedRepairState) usedHosts() []HostKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := make(map[HostKey]bool, len(s.pieceHosts))
+	hosts := make([]HostKey, 0, len(s.pieceHosts))
+	for _, hostKey := range s.pieceHosts {
+		if !seen[hostKey] {
+			seen[hostKey] = true
+			hosts = append(hosts, hostKey)
+		}
+	}
+	return hosts
+}
+
+// usedCopier reports whether any piece of this file was ever repaired via
+// the local-copy path, for tests asserting which path a repair took.
+func (s *sharedRepairState) usedCopier() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.copiedAny
+}
+
+// usedPuller reports whether any piece of this file was ever repaired by
+// downloading surviving shards from remote hosts, for tests asserting
+// which path a repair took.
+func (s *sharedRepairState) usedPuller() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pulledAny
+}
+
+// fail records that hostKey failed while servicing this repair and marks
+// the state as failed, cancelling any in-flight uploads exactly once so
+// they don't leak.
+func (s *sharedRepairState) fail(hostKey HostKey, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hostKey != "" {
+		s.failedHosts[hostKey] = true
+	}
+	if s.err == nil {
+		s.err = err
+	}
+	if !s.done {
+		s.done = true
+		if s.cancel != nil {
+			s.cancel()
+		}
+	}
+}
+
+// readyForFinish reports whether every piece the copier and puller were
+// responsible for has succeeded, meaning the finisher may safely run. It
+// returns false once the state has failed, since the finisher must never
+// run on a partial result.
+func (s *sharedRepairState) readyForFinish() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err == nil && !s.done && s.pending == 0
+}
+
+// markDone marks the repair as finished, whether it succeeded or not. Safe
+// to call more than once.
+func (s *sharedRepairState) markDone() {
+	s.mu.Lock()
+	s.done = true
+	s.mu.Unlock()
+}
+
+// hasFailed reports whether the repair has already been marked failed.
+func (s *sharedRepairState) hasFailed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err != nil
+}
@@ -0,0 +1,177 @@
+package renter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// RepairStrategy decides which of a file's pieces need repair right now,
+// which hosts should service a given piece, and whether an otherwise-due
+// repair should be deferred. It lets the renter trade off repair latency
+// against the amount of host/contract churn frequent repairs cause.
+type RepairStrategy interface {
+	// SelectPiecesToRepair returns the indices of pieces that should be
+	// repaired for fileInfo right now.
+	SelectPiecesToRepair(fileInfo modules.FileInfo) []uint64
+
+	// SelectHostsForPiece orders candidates by preference for servicing
+	// the given piece.
+	SelectHostsForPiece(piece uint64, candidates []HostKey) []HostKey
+
+	// ShouldDeferRepair reports whether repair of fileInfo should be
+	// postponed rather than started immediately.
+	ShouldDeferRepair(fileInfo modules.FileInfo) bool
+}
+
+// Repair strategy names, as stored in modules.RenterSettings.RepairStrategy
+// and accepted by the /renter/repairstrategy API call. modules.RenterSettings
+// only holds this serializable name (plus any parameters, like
+// RepairStrategyFloor) rather than a renter.RepairStrategy value directly:
+// renter already imports modules, so a RepairStrategy-typed field on
+// modules.RenterSettings would require modules to import renter right back,
+// an import cycle. Resolving the name to a concrete strategy happens here,
+// inside the renter package, instead.
+const (
+	RepairStrategyEager     = "eager"
+	RepairStrategyThreshold = "threshold"
+)
+
+// eagerRepairStrategy repairs a file as soon as its redundancy drops below
+// full health. This is the renter's historical behavior.
+type eagerRepairStrategy struct {
+	r *Renter
+}
+
+// SelectPiecesToRepair implements RepairStrategy.
+func (s eagerRepairStrategy) SelectPiecesToRepair(fileInfo modules.FileInfo) []uint64 {
+	return s.r.degradedPieces(fileInfo)
+}
+
+// SelectHostsForPiece implements RepairStrategy.
+func (eagerRepairStrategy) SelectHostsForPiece(_ uint64, candidates []HostKey) []HostKey {
+	return candidates
+}
+
+// ShouldDeferRepair implements RepairStrategy. The eager strategy never
+// defers.
+func (eagerRepairStrategy) ShouldDeferRepair(_ modules.FileInfo) bool {
+	return false
+}
+
+// thresholdRepairStrategy waits until a file's redundancy falls below
+// Floor before repairing, then repairs every degraded piece in a single
+// batch. This amortizes the contract/host churn that repairing on every
+// small redundancy dip would otherwise cause.
+type thresholdRepairStrategy struct {
+	r     *Renter
+	Floor float64
+}
+
+// SelectPiecesToRepair implements RepairStrategy. It returns nothing until
+// the file's redundancy has actually crossed the floor.
+func (s thresholdRepairStrategy) SelectPiecesToRepair(fileInfo modules.FileInfo) []uint64 {
+	if fileInfo.Redundancy >= s.Floor {
+		return nil
+	}
+	return s.r.degradedPieces(fileInfo)
+}
+
+// SelectHostsForPiece implements RepairStrategy.
+func (thresholdRepairStrategy) SelectHostsForPiece(_ uint64, candidates []HostKey) []HostKey {
+	return candidates
+}
+
+// ShouldDeferRepair implements RepairStrategy.
+func (s thresholdRepairStrategy) ShouldDeferRepair(fileInfo modules.FileInfo) bool {
+	return fileInfo.Redundancy >= s.Floor
+}
+
+// MarkPiecesDegraded records that the given piece indices of siaPath are no
+// longer healthy, e.g. because the renter's contractor or host database
+// detected that the host holding them went offline. degradedPieces reports
+// these indices (and only these) until they're repaired.
+func (r *Renter) MarkPiecesDegraded(siaPath string, pieces []uint64) {
+	r.pieceHealthMu.Lock()
+	defer r.pieceHealthMu.Unlock()
+	if r.pieceHealth[siaPath] == nil {
+		r.pieceHealth[siaPath] = make(map[uint64]bool)
+	}
+	for _, piece := range pieces {
+		r.pieceHealth[siaPath][piece] = true
+	}
+}
+
+// markPiecesHealthy clears siaPath's degraded marker for the given piece
+// indices, once repairFile has successfully recovered them.
+func (r *Renter) markPiecesHealthy(siaPath string, pieces []uint64) {
+	r.pieceHealthMu.Lock()
+	defer r.pieceHealthMu.Unlock()
+	for _, piece := range pieces {
+		delete(r.pieceHealth[siaPath], piece)
+	}
+}
+
+// degradedPieces reports the indices of fileInfo's pieces that are
+// currently known to be degraded, as recorded by MarkPiecesDegraded. Unlike
+// deriving a piece count from the gap between fileInfo.Redundancy and a
+// high-water mark, this always names the actual pieces that need repair,
+// so the copier/puller pools never end up working on the wrong indices.
+func (r *Renter) degradedPieces(fileInfo modules.FileInfo) []uint64 {
+	r.pieceHealthMu.Lock()
+	defer r.pieceHealthMu.Unlock()
+
+	degraded := r.pieceHealth[fileInfo.SiaPath]
+	if len(degraded) == 0 {
+		return nil
+	}
+	pieces := make([]uint64, 0, len(degraded))
+	for piece := range degraded {
+		pieces = append(pieces, piece)
+	}
+	sort.Slice(pieces, func(i, j int) bool { return pieces[i] < pieces[j] })
+	return pieces
+}
+
+// resolveRepairStrategy turns a strategy name and its parameters, as stored
+// in modules.RenterSettings, into a concrete RepairStrategy.
+func (r *Renter) resolveRepairStrategy(name string, floor float64) (RepairStrategy, error) {
+	switch name {
+	case "", RepairStrategyEager:
+		return eagerRepairStrategy{r: r}, nil
+	case RepairStrategyThreshold:
+		return thresholdRepairStrategy{r: r, Floor: floor}, nil
+	default:
+		return nil, fmt.Errorf("unknown repair strategy %q", name)
+	}
+}
+
+// repairStrategy returns the renter's currently active RepairStrategy,
+// resolved from the name and floor stored in modules.RenterSettings.
+func (r *Renter) repairStrategy() RepairStrategy {
+	r.mu.RLock()
+	name, floor := r.settings.RepairStrategy, r.settings.RepairStrategyFloor
+	r.mu.RUnlock()
+
+	// r.settings is validated on the way in by SetRepairStrategy, so the
+	// only way resolveRepairStrategy can fail here is an unset name, which
+	// it already maps to the eager strategy.
+	strategy, _ := r.resolveRepairStrategy(name, floor)
+	return strategy
+}
+
+// SetRepairStrategy changes the renter's active RepairStrategy at runtime
+// by validating and storing name/floor in modules.RenterSettings. It takes
+// effect on the next repair scan; any repair already underway keeps
+// running under the strategy it started with.
+func (r *Renter) SetRepairStrategy(name string, floor float64) error {
+	if _, err := r.resolveRepairStrategy(name, floor); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.settings.RepairStrategy = name
+	r.settings.RepairStrategyFloor = floor
+	r.mu.Unlock()
+	return nil
+}
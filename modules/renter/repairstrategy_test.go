@@ -0,0 +1,46 @@
+package renter
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestDegradedPieces verifies that degradedPieces reports exactly the piece
+// indices most recently marked degraded, not a synthetic run derived from a
+// redundancy delta, and that repairing a piece clears it from the set.
+func TestDegradedPieces(t *testing.T) {
+	r := New()
+	defer r.Close()
+
+	fileInfo := modules.FileInfo{SiaPath: "myfile"}
+
+	if got := r.degradedPieces(fileInfo); len(got) != 0 {
+		t.Fatalf("expected no degraded pieces before any are marked, got %v", got)
+	}
+
+	r.MarkPiecesDegraded("myfile", []uint64{5, 2, 8})
+	got := r.degradedPieces(fileInfo)
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	want := []uint64{2, 5, 8}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("degradedPieces = %v, want %v", got, want)
+	}
+
+	// Healing piece 5 should leave the other two still degraded.
+	r.markPiecesHealthy("myfile", []uint64{5})
+	got = r.degradedPieces(fileInfo)
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	want = []uint64{2, 8}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("degradedPieces after healing piece 5 = %v, want %v", got, want)
+	}
+
+	// A file that was never marked degraded shouldn't borrow another file's
+	// degraded set.
+	if got := r.degradedPieces(modules.FileInfo{SiaPath: "otherfile"}); len(got) != 0 {
+		t.Fatalf("expected otherfile to have no degraded pieces, got %v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Client makes calls against a running renter's API.
+type Client struct {
+	Address string
+	Password string
+
+	httpClient http.Client
+}
+
+// New returns a Client that talks to the API served at address.
+func New(address string) *Client {
+	return &Client{Address: address}
+}
+
+// get performs a GET request against resource and decodes the JSON
+// response into obj, if obj is non-nil.
+func (c *Client) get(resource string, obj interface{}) error {
+	resp, err := c.httpClient.Get("http://" + c.Address + resource)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
+			return fmt.Errorf("request to %v failed with status %v", resource, resp.StatusCode)
+		}
+		return fmt.Errorf("request to %v failed: %v", resource, apiErr.Message)
+	}
+	if obj == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(obj)
+}
+
+// post performs a POST request against resource with the given form
+// values and decodes the JSON response into obj, if obj is non-nil.
+func (c *Client) post(resource, values string, obj interface{}) error {
+	req, err := http.NewRequest("POST", "http://"+c.Address+resource, bytes.NewBufferString(values))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
+			return fmt.Errorf("request to %v failed with status %v", resource, resp.StatusCode)
+		}
+		return fmt.Errorf("request to %v failed: %v", resource, apiErr.Message)
+	}
+	if obj == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(obj)
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"github.com/NebulousLabs/Sia/modules/renter"
+)
+
+// RenterHostActivityGet requests the /renter/hostactivity resource, which
+// reports the number of in-flight requests per host the renter is
+// currently tracking.
+func (c *Client) RenterHostActivityGet() (map[renter.HostKey]int, error) {
+	var counts map[renter.HostKey]int
+	err := c.get("/renter/hostactivity", &counts)
+	return counts, err
+}
+
+// RenterHostActivityTotalsGet requests the /renter/hostactivitytotals
+// resource, which reports the cumulative number of requests ever issued
+// per host.
+func (c *Client) RenterHostActivityTotalsGet() (map[renter.HostKey]int, error) {
+	var totals map[renter.HostKey]int
+	err := c.get("/renter/hostactivitytotals", &totals)
+	return totals, err
+}
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"github.com/NebulousLabs/Sia/modules/renter"
+)
+
+// RenterProgressGet requests the /renter/progress resource, which reports
+// the origin/current/highest byte counters of the most recent repair run of
+// every file the renter has repaired, keyed by SiaPath. Uploads and
+// downloads are not reported.
+func (c *Client) RenterProgressGet() (map[string]renter.ProgressInfo, error) {
+	var progress map[string]renter.ProgressInfo
+	err := c.get("/renter/progress", &progress)
+	return progress, err
+}
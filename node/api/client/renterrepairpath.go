@@ -0,0 +1,17 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// RenterRepairPathGet requests the /renter/repairpath/:siapath resource,
+// returning which pool ("copier", "puller", or "copier+puller") most
+// recently handled siaPath's repair.
+func (c *Client) RenterRepairPathGet(siaPath string) (string, error) {
+	var resp struct {
+		Path string `json:"path"`
+	}
+	err := c.get(fmt.Sprintf("/renter/repairpath/%s", url.PathEscape(siaPath)), &resp)
+	return resp.Path, err
+}
@@ -0,0 +1,13 @@
+package client
+
+import (
+	"fmt"
+)
+
+// RenterRepairStrategyPost requests the /renter/repairstrategy resource,
+// switching the renter's active RepairStrategy to name ("eager" or
+// "threshold"). floor is only used when name is "threshold".
+func (c *Client) RenterRepairStrategyPost(name string, floor float64) error {
+	values := fmt.Sprintf("strategy=%s&floor=%f", name, floor)
+	return c.post("/renter/repairstrategy", values, nil)
+}
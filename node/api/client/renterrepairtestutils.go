@@ -0,0 +1,21 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenterTestHostFetcherPost requests the /renter/testhostfetcher resource,
+// wiring the renter's HostPieceFetcher to a stub that always succeeds. It
+// exists for integration tests that need to exercise the puller pool
+// without a live renter-host protocol implementation.
+func (c *Client) RenterTestHostFetcherPost() error {
+	return c.post("/renter/testhostfetcher", "", nil)
+}
+
+// RenterTestPieceHostsPost requests the /renter/testpiecehosts resource,
+// recording which hosts are known to hold a given piece of siaPath.
+func (c *Client) RenterTestPieceHostsPost(siaPath string, piece uint64, hosts []string) error {
+	values := fmt.Sprintf("siapath=%s&piece=%d&hosts=%s", siaPath, piece, strings.Join(hosts, ","))
+	return c.post("/renter/testpiecehosts", values, nil)
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenterScanAndRepairPost requests the /renter/scanandrepair resource,
+// triggering an immediate repair scan of siaPath instead of waiting for the
+// renter's background repair scan loop. degradedPieces are the piece
+// indices known to need repair right now.
+func (c *Client) RenterScanAndRepairPost(siaPath string, redundancy float64, localPath string, degradedPieces []uint64) error {
+	pieces := make([]string, len(degradedPieces))
+	for i, piece := range degradedPieces {
+		pieces[i] = fmt.Sprintf("%d", piece)
+	}
+	values := fmt.Sprintf("siapath=%s&redundancy=%f&localpath=%s&degradedpieces=%s",
+		siaPath, redundancy, localPath, strings.Join(pieces, ","))
+	return c.post("/renter/scanandrepair", values, nil)
+}
@@ -1,6 +1,9 @@
 package renter
 
 import (
+	"fmt"
+	"os"
+	"sync"
 	"testing"
 
 	"github.com/NebulousLabs/Sia/modules"
@@ -39,6 +42,8 @@ func TestRenter(t *testing.T) {
 		{"UploadDownload", testUploadDownload},
 		{"TestRenterLocalRepair", testRenterLocalRepair},
 		{"TestRenterRemoteRepair", testRenterRemoteRepair},
+		{"TestHostLoadBalancing", testHostLoadBalancing},
+		{"TestRepairStrategy", testRepairStrategy},
 	}
 	// Run subtests
 	for _, subtest := range subTests {
@@ -122,6 +127,31 @@ func testRenterLocalRepair(t *testing.T, tg *siatest.TestGroup) {
 	if err := renter.WaitForUploadRedundancy(remoteFile, fi.Redundancy); err != nil {
 		t.Fatal("File wasn't repaired", err)
 	}
+
+	// Trigger the copier/puller/finisher repair machinery directly instead
+	// of waiting on the renter's background scan, so the test doesn't race
+	// its timer. localCopy points at a real file on disk, standing in for
+	// the local copy of the uploaded file the renter would normally still
+	// have, so this exercises readPieceFromLocalFile for real rather than
+	// just asserting against it.
+	localCopy, err := os.CreateTemp("", "repair-local-copy-*")
+	if err != nil {
+		t.Fatal("failed to create local copy stand-in", err)
+	}
+	defer os.Remove(localCopy.Name())
+	if _, err := localCopy.Write(make([]byte, fileSize)); err != nil {
+		t.Fatal("failed to populate local copy stand-in", err)
+	}
+	localCopy.Close()
+	if err := renter.TriggerRepairScan(remoteFile.SiaPath(), expectedRedundancy, localCopy.Name(), []uint64{0}); err != nil {
+		t.Fatal("Failed to trigger repair scan", err)
+	}
+
+	// Since the local file was never deleted, the repair should have used
+	// the copier path rather than downloading from hosts.
+	if err := renter.WaitForRepairPath(remoteFile.SiaPath(), "copier"); err != nil {
+		t.Fatal("Repair didn't use the copier path", err)
+	}
 	// We should be able to download
 	if _, err := renter.DownloadByStream(remoteFile); err != nil {
 		t.Fatal("Failed to download file", err)
@@ -170,15 +200,189 @@ func testRenterRemoteRepair(t *testing.T, tg *siatest.TestGroup) {
 	if _, err := renter.DownloadByStream(remoteFile); err != nil {
 		t.Fatal("Failed to download file", err)
 	}
-	// Bring up new parity hosts and check if redundancy increments again.
+	// Bring up new parity hosts and check if the repair completes. Poll the
+	// renter's repair progress before checking FileInfo().Redundancy, which
+	// alone can't tell "hasn't started" from "barely started".
 	if err := tg.AddNodeN(node.HostTemplate, int(parityPieces)); err != nil {
 		t.Fatal("Failed to create a new host", err)
 	}
+
+	// Trigger the copier/puller/finisher repair machinery directly instead
+	// of waiting on the renter's background scan, so the test doesn't race
+	// its timer. Since this package doesn't implement the real
+	// renter-host protocol, wire a stub fetcher and register which (test)
+	// hosts hold each missing piece before triggering the scan, standing
+	// in for the contractor bookkeeping a full renter would already have.
+	if err := renter.EnableTestHostFetcher(); err != nil {
+		t.Fatal("Failed to enable test host fetcher", err)
+	}
+	degraded := make([]uint64, parityPieces)
+	for i := uint64(0); i < parityPieces; i++ {
+		degraded[i] = i
+		if err := renter.RegisterPieceHosts(remoteFile.SiaPath(), i, []string{fmt.Sprintf("host%d", i)}); err != nil {
+			t.Fatal("Failed to register piece hosts", err)
+		}
+	}
+	// No local copy: the renter's local file was deleted above.
+	if err := renter.TriggerRepairScan(remoteFile.SiaPath(), expectedRedundancy, "", degraded); err != nil {
+		t.Fatal("Failed to trigger repair scan", err)
+	}
+
+	if err := renter.WaitForRepairProgress(remoteFile.SiaPath(), uint64(fileSize)); err != nil {
+		t.Fatal("Repair progress didn't reach completion", err)
+	}
 	if err := renter.WaitForUploadRedundancy(remoteFile, fi.Redundancy); err != nil {
 		t.Fatal("File wasn't repaired", err)
 	}
+	// Since the local file was deleted, the repair should have used the
+	// puller path, reconstructing the missing pieces from surviving hosts.
+	if err := renter.WaitForRepairPath(remoteFile.SiaPath(), "puller"); err != nil {
+		t.Fatal("Repair didn't use the puller path", err)
+	}
 	// We should be able to download
 	if _, err := renter.DownloadByStream(remoteFile); err != nil {
 		t.Fatal("Failed to download file", err)
 	}
 }
+
+// testHostLoadBalancing tests that when several hosts can serve a given
+// piece, concurrent downloads get spread across them instead of piling up
+// on whichever host happens to be picked first.
+func testHostLoadBalancing(t *testing.T, tg *siatest.TestGroup) {
+	// Grab the first of the group's renters
+	renter := tg.Renters()[0]
+
+	// Upload a file with a piece on every host in the group.
+	dataPieces := uint64(1)
+	parityPieces := uint64(len(tg.Hosts())) - dataPieces
+	_, remoteFile, err := renter.UploadNewFileBlocking(100, dataPieces, parityPieces)
+	if err != nil {
+		t.Fatal("Failed to upload a file for testing: ", err)
+	}
+
+	// Issue a number of parallel stream downloads of the same file, which
+	// should get load-balanced across the hosts serving it.
+	numDownloads := 20
+	var wg sync.WaitGroup
+	for i := 0; i < numDownloads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := renter.DownloadByStream(remoteFile); err != nil {
+				t.Error("Failed to download file", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The cumulative request count for each host that served the file
+	// should stay within a small delta of the others. Totals, not the
+	// in-flight counts, since every download has already finished by the
+	// time we ask and the in-flight counts are back to zero. An empty
+	// result means the scheduler never actually consulted the load
+	// balancer, which is a failure in its own right, not something the
+	// delta check below should be allowed to pass trivially.
+	counts, err := renter.RenterHostActivityTotalsGet()
+	if err != nil {
+		t.Fatal("Failed to get host activity", err)
+	}
+	if len(counts) == 0 {
+		t.Fatal("expected per-host request counts to be reported, got none")
+	}
+	if len(counts) != len(tg.Hosts()) {
+		t.Fatalf("expected all %v hosts to have served requests, only %v did: %v", len(tg.Hosts()), len(counts), counts)
+	}
+	var min, max int
+	first := true
+	for _, count := range counts {
+		if first {
+			min, max = count, count
+			first = false
+			continue
+		}
+		if count < min {
+			min = count
+		}
+		if count > max {
+			max = count
+		}
+	}
+	const maxDelta = 2
+	if max-min > maxDelta {
+		t.Fatalf("host request counts are too uneven: min %v, max %v, counts %v", min, max, counts)
+	}
+}
+
+// testRepairStrategy switches the renter's repair strategy mid-test and
+// checks that the observable repair behavior matches whichever strategy is
+// active at the time a host is removed.
+func testRepairStrategy(t *testing.T, tg *siatest.TestGroup) {
+	// Grab the first of the group's renters
+	renter := tg.Renters()[0]
+
+	fileSize := int(modules.SectorSize)
+	dataPieces := uint64(1)
+	parityPieces := uint64(len(tg.Hosts())) - dataPieces
+
+	// Switch to the threshold strategy with a floor equal to the redundancy
+	// a single missing host produces, so a single lost host should NOT
+	// trigger a repair.
+	fullRedundancy := float64(dataPieces+parityPieces) / float64(dataPieces)
+	droppedRedundancy := float64(dataPieces+parityPieces-1) / float64(dataPieces)
+	floor := (fullRedundancy + droppedRedundancy) / 2
+	if err := renter.SetThresholdRepairStrategy(floor - 0.5); err != nil {
+		t.Fatal("Failed to set threshold repair strategy", err)
+	}
+
+	_, remoteFile, err := renter.UploadNewFileBlocking(fileSize, dataPieces, parityPieces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := renter.FileInfo(remoteFile)
+	if err != nil {
+		t.Fatal("failed to get file info", err)
+	}
+
+	// Remove one host. Redundancy should drop, but because the threshold
+	// strategy's floor hasn't been crossed, no repair should be triggered.
+	if err := tg.RemoveNode(tg.Hosts()[0]); err != nil {
+		t.Fatal("Failed to shutdown host", err)
+	}
+	if err := renter.WaitForDecreasingRedundancy(remoteFile, droppedRedundancy); err != nil {
+		t.Fatal("Redundancy isn't decreasing", err)
+	}
+
+	// Trigger a repair scan directly while the threshold strategy is still
+	// active. Since its floor hasn't been crossed, ShouldDeferRepair should
+	// defer the repair, so no repair path gets recorded.
+	if err := renter.EnableTestHostFetcher(); err != nil {
+		t.Fatal("Failed to enable test host fetcher", err)
+	}
+	if err := renter.RegisterPieceHosts(remoteFile.SiaPath(), 0, []string{"host0"}); err != nil {
+		t.Fatal("Failed to register piece hosts", err)
+	}
+	if err := renter.TriggerRepairScan(remoteFile.SiaPath(), droppedRedundancy, "", []uint64{0}); err != nil {
+		t.Fatal("Failed to trigger repair scan", err)
+	}
+	if path, err := renter.RenterRepairPathGet(remoteFile.SiaPath()); err == nil {
+		t.Fatalf("expected no repair path while the threshold strategy defers, got %q", path)
+	}
+
+	// Now switch back to the eager strategy and bring up a new host. The
+	// eager strategy should repair the file immediately.
+	if err := renter.SetEagerRepairStrategy(); err != nil {
+		t.Fatal("Failed to set eager repair strategy", err)
+	}
+	if err := tg.AddNodes(node.HostTemplate); err != nil {
+		t.Fatal("Failed to create a new host", err)
+	}
+	if err := renter.WaitForUploadRedundancy(remoteFile, fi.Redundancy); err != nil {
+		t.Fatal("File wasn't repaired under the eager strategy", err)
+	}
+	if err := renter.TriggerRepairScan(remoteFile.SiaPath(), droppedRedundancy, "", []uint64{0}); err != nil {
+		t.Fatal("Failed to trigger repair scan", err)
+	}
+	if err := renter.WaitForRepairPath(remoteFile.SiaPath(), "puller"); err != nil {
+		t.Fatal("Repair didn't run under the eager strategy", err)
+	}
+}
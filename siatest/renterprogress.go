@@ -0,0 +1,29 @@
+package siatest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NebulousLabs/Sia/build"
+)
+
+// WaitForRepairProgress blocks until the renter reports that siaPath's
+// repair progress has reached target, or the retry budget is exhausted.
+// It replaces the previous pattern of polling FileInfo().Redundancy, which
+// can't distinguish "hasn't started" from "barely started".
+func (tn *TestNode) WaitForRepairProgress(siaPath string, target uint64) error {
+	return build.Retry(600, 100*time.Millisecond, func() error {
+		progress, err := tn.RenterProgressGet()
+		if err != nil {
+			return err
+		}
+		p, ok := progress[siaPath]
+		if !ok {
+			return fmt.Errorf("no repair progress reported yet for %v", siaPath)
+		}
+		if p.Current < target {
+			return fmt.Errorf("repair progress for %v is %v/%v, want %v", siaPath, p.Current, p.Highest, target)
+		}
+		return nil
+	})
+}
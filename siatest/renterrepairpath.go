@@ -0,0 +1,24 @@
+package siatest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NebulousLabs/Sia/build"
+)
+
+// WaitForRepairPath blocks until the renter has recorded a repair path for
+// siaPath and reports that it matches want ("copier", "puller", or
+// "copier+puller").
+func (tn *TestNode) WaitForRepairPath(siaPath, want string) error {
+	return build.Retry(600, 100*time.Millisecond, func() error {
+		got, err := tn.RenterRepairPathGet(siaPath)
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("repair path for %v is %v, want %v", siaPath, got, want)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,14 @@
+package siatest
+
+// SetEagerRepairStrategy switches the renter to the eager repair strategy,
+// which repairs a file as soon as its redundancy drops.
+func (tn *TestNode) SetEagerRepairStrategy() error {
+	return tn.RenterRepairStrategyPost("eager", 0)
+}
+
+// SetThresholdRepairStrategy switches the renter to the threshold repair
+// strategy, which waits until redundancy falls below floor before
+// repairing all degraded pieces in a single batch.
+func (tn *TestNode) SetThresholdRepairStrategy(floor float64) error {
+	return tn.RenterRepairStrategyPost("threshold", floor)
+}
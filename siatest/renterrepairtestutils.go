@@ -0,0 +1,15 @@
+package siatest
+
+// EnableTestHostFetcher wires the renter's HostPieceFetcher to a stub that
+// always succeeds, so integration tests can exercise the puller pool
+// without a live renter-host protocol implementation.
+func (tn *TestNode) EnableTestHostFetcher() error {
+	return tn.RenterTestHostFetcherPost()
+}
+
+// RegisterPieceHosts records which hosts are known to hold a given piece of
+// siaPath, standing in for the contractor/host database bookkeeping a full
+// renter would do as contracts are formed.
+func (tn *TestNode) RegisterPieceHosts(siaPath string, piece uint64, hosts []string) error {
+	return tn.RenterTestPieceHostsPost(siaPath, piece, hosts)
+}
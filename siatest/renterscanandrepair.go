@@ -0,0 +1,8 @@
+package siatest
+
+// TriggerRepairScan asks the renter to immediately run a repair scan for
+// siaPath against the given degraded piece indices, instead of waiting for
+// the renter's background repair scan loop to notice on its own schedule.
+func (tn *TestNode) TriggerRepairScan(siaPath string, redundancy float64, localPath string, degradedPieces []uint64) error {
+	return tn.RenterScanAndRepairPost(siaPath, redundancy, localPath, degradedPieces)
+}